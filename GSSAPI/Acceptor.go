@@ -0,0 +1,43 @@
+package GSSAPI
+
+import "errors"
+
+// Acceptor drives the server side of a SPNEGO negotiation. It is a thin
+// wrapper around Negotiator, configured with a mechSelector that only agrees
+// to one of prefs, chosen in the server's own preference order regardless of
+// how the client ordered its MechTypes; the accept-side state machine itself
+// lives in Negotiator so it is not duplicated here.
+type Acceptor struct {
+	*Negotiator
+}
+
+// NewAcceptor creates an Acceptor that will only agree to one of prefs,
+// chosen in the server's own preference order regardless of how the client
+// ordered its MechTypes. Set the returned Acceptor's RequireMIC to demand a
+// mechListMIC from the initiator before accept-completed.
+func NewAcceptor(prefs ...Mechanism) (*Acceptor, error) {
+	if len(prefs) == 0 {
+		return nil, errors.New("GSSAPI: at least one mechanism is required")
+	}
+	neg := &Negotiator{
+		role: roleAcceptor,
+		mechSelector: func(clientMechs MechTypeList) (Mechanism, int, error) {
+			for _, pm := range prefs {
+				for i, co := range clientMechs {
+					if oidEqual(co, pm.OID()) {
+						return pm, i, nil
+					}
+				}
+			}
+			return nil, -1, errors.New("GSSAPI: no mutually supported mechanism")
+		},
+	}
+	return &Acceptor{Negotiator: neg}, nil
+}
+
+// Accept processes the initiator's first token on the wire - an
+// InitialContextToken wrapped NegTokenInit - and returns the NegTokenResp
+// bytes to send back, the only reply that carries SupportedMech.
+func (a *Acceptor) Accept(initialToken []byte) (outgoing []byte, done bool, err error) {
+	return a.Negotiator.Step(initialToken)
+}
@@ -0,0 +1,59 @@
+package GSSAPI
+
+import (
+	"testing"
+
+	"github.com/jcmturner/asn1"
+)
+
+func TestAcceptorRejectsNegTokenRespAsFirstToken(t *testing.T) {
+	oid := asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 2, 2, 30, 1}
+	a, err := NewAcceptor(&fakeMechanism{oid: oid})
+	if err != nil {
+		t.Fatalf("NewAcceptor: %v", err)
+	}
+	resp := NegTokenResp{NegState: NegStateAcceptIncomplete, ResponseToken: []byte("bogus")}
+	b, err := resp.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if _, _, err := a.Accept(b); err == nil {
+		t.Fatal("expected an error for a NegTokenResp as the first token, got nil")
+	}
+}
+
+func TestNewAcceptorRequiresAtLeastOneMechanism(t *testing.T) {
+	if _, err := NewAcceptor(); err == nil {
+		t.Fatal("expected an error when no mechanisms are given")
+	}
+}
+
+func TestAcceptorSelectsOwnPreferenceOrderOverClients(t *testing.T) {
+	serverPreferred := &fakeMechanism{oid: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 2, 2, 30, 2}}
+	serverSecondChoice := &fakeMechanism{oid: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 2, 2, 30, 3}}
+	a, err := NewAcceptor(serverPreferred, serverSecondChoice)
+	if err != nil {
+		t.Fatalf("NewAcceptor: %v", err)
+	}
+
+	// Client offers the server's second choice first.
+	nti := NegTokenInit{
+		MechTypes: MechTypeList{serverSecondChoice.OID(), serverPreferred.OID()},
+		MechToken: []byte("hello"),
+	}
+	st := SPNEGOToken{Init: nti}
+	b, err := st.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	if _, _, err := a.Accept(b); err != nil {
+		t.Fatalf("Accept: %v", err)
+	}
+	if serverPreferred.calls != 1 {
+		t.Errorf("expected the server's own preferred mechanism to be selected, got %d calls on it", serverPreferred.calls)
+	}
+	if serverSecondChoice.calls != 0 {
+		t.Errorf("expected the server's second choice not to be selected, got %d calls on it", serverSecondChoice.calls)
+	}
+}
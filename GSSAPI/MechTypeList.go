@@ -0,0 +1,15 @@
+package GSSAPI
+
+import "github.com/jcmturner/asn1"
+
+// MechType is the object identifier of a GSS-API mechanism as advertised in
+// a NegTokenInit/NegTokenResp.
+type MechType asn1.ObjectIdentifier
+
+// MechTypeList is the ordered list of mechanism OIDs a NegTokenInit
+// advertises, most preferred first (RFC 4178 section 4.2.1).
+type MechTypeList []asn1.ObjectIdentifier
+
+// ContextFlags are the optional negTokenInit context request flags,
+// retained from RFC 2478 for backward compatibility only.
+type ContextFlags asn1.BitString
@@ -0,0 +1,75 @@
+package GSSAPI
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/jcmturner/asn1"
+)
+
+// KerberosOID is the object identifier of the Kerberos V5 GSS-API mechanism.
+var KerberosOID = asn1.ObjectIdentifier{1, 2, 840, 113554, 1, 2, 2}
+
+// NTLMSSPOID is the object identifier Microsoft uses to advertise NTLMSSP as
+// a SPNEGO mechanism.
+var NTLMSSPOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 2, 2, 10}
+
+// Mechanism is a GSS-API mechanism provider that SPNEGO can negotiate and
+// drive. Implementations live outside this package (e.g. a Kerberos client
+// or an NTLMSSP implementation) and register themselves with
+// RegisterMechanism so that an acceptor's default selection can find them
+// without this package depending on them directly. An initiator advertises
+// only the mechanisms passed to NewInitiator, not the registered ones.
+type Mechanism interface {
+	// OID is this mechanism's object identifier.
+	OID() asn1.ObjectIdentifier
+	// InitialToken produces the first mechToken an initiator sends for this
+	// mechanism.
+	InitialToken() ([]byte, error)
+	// Step processes one round of the mechanism's own negotiation, on
+	// either the initiator or the acceptor side: b is the token just
+	// received from the peer (nil on an acceptor's first call for a
+	// mechanism whose mechToken was empty), and out is the token to send
+	// back, if any. done reports whether the security context is now
+	// established.
+	Step(b []byte) (out []byte, done bool, err error)
+	// GetMIC computes a per-message integrity check over b, used to sign the
+	// mechListMIC.
+	GetMIC(b []byte) ([]byte, error)
+	// VerifyMIC checks mic against b as produced by the peer's GetMIC.
+	VerifyMIC(b, mic []byte) error
+}
+
+var (
+	mechanismsMu sync.RWMutex
+	mechanisms   = make(map[string]Mechanism)
+)
+
+// RegisterMechanism makes a Mechanism available for SPNEGO negotiation under
+// its own OID. Mechanism providers call this from an init function.
+func RegisterMechanism(m Mechanism) {
+	mechanismsMu.Lock()
+	defer mechanismsMu.Unlock()
+	mechanisms[m.OID().String()] = m
+}
+
+// LookupMechanism returns the Mechanism registered for oid, if any.
+func LookupMechanism(oid asn1.ObjectIdentifier) (Mechanism, bool) {
+	mechanismsMu.RLock()
+	defer mechanismsMu.RUnlock()
+	m, ok := mechanisms[oid.String()]
+	return m, ok
+}
+
+// SelectMechanism returns the first mechanism in preference order (most
+// preferred first) that is registered, along with its index in mechTypes.
+// It is the default selector an acceptor uses to pick amongst a client's
+// offered mechTypes when no other selector is configured.
+func SelectMechanism(mechTypes MechTypeList) (Mechanism, int, error) {
+	for i, oid := range mechTypes {
+		if m, ok := LookupMechanism(oid); ok {
+			return m, i, nil
+		}
+	}
+	return nil, -1, fmt.Errorf("no registered mechanism found amongst %d offered mechTypes", len(mechTypes))
+}
@@ -0,0 +1,83 @@
+package GSSAPI
+
+import (
+	"testing"
+
+	"github.com/jcmturner/asn1"
+)
+
+func TestRegisterLookupSelectMechanism(t *testing.T) {
+	preferred := &fakeMechanism{oid: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 2, 2, 20, 1}}
+	fallback := &fakeMechanism{oid: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 2, 2, 20, 2}}
+	RegisterMechanism(preferred)
+	RegisterMechanism(fallback)
+
+	got, ok := LookupMechanism(preferred.OID())
+	if !ok || got != preferred {
+		t.Fatalf("LookupMechanism(%s) = %v, %v; want %v, true", preferred.OID(), got, ok, preferred)
+	}
+
+	m, i, err := SelectMechanism(MechTypeList{fallback.OID(), preferred.OID()})
+	if err != nil {
+		t.Fatalf("SelectMechanism: %v", err)
+	}
+	if m != fallback || i != 0 {
+		t.Errorf("SelectMechanism = %v, %d; want the first registered offer (%v, 0)", m, i, fallback)
+	}
+}
+
+func TestSelectMechanismNoneRegistered(t *testing.T) {
+	unregistered := asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 2, 2, 20, 99}
+	if _, _, err := SelectMechanism(MechTypeList{unregistered}); err == nil {
+		t.Fatal("expected an error when no offered mechType is registered, got nil")
+	}
+}
+
+func TestStepInitiateFallsBackToAcceptorChosenMechanism(t *testing.T) {
+	preferred := &fakeMechanism{oid: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 2, 2, 20, 3}}
+	fallback := &fakeMechanism{oid: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 2, 2, 20, 4}}
+
+	neg, _, _, err := NewInitiator(preferred, fallback)
+	if err != nil {
+		t.Fatalf("NewInitiator: %v", err)
+	}
+
+	ntr := NegTokenResp{
+		NegState:      NegStateAcceptIncomplete,
+		SupportedMech: MechType(fallback.OID()),
+		ResponseToken: []byte("challenge"),
+	}
+	b, err := ntr.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	if _, _, err := neg.Step(b); err != nil {
+		t.Fatalf("Step: %v", err)
+	}
+	if fallback.calls != 1 {
+		t.Errorf("expected the acceptor-chosen mechanism to be stepped once, got %d calls", fallback.calls)
+	}
+	if preferred.calls != 0 {
+		t.Errorf("expected the preferred mechanism not to be stepped once the acceptor chose another, got %d calls", preferred.calls)
+	}
+}
+
+func TestStepInitiateUnknownSupportedMechIsAnError(t *testing.T) {
+	preferred := &fakeMechanism{oid: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 2, 2, 20, 5}}
+	neg, _, _, err := NewInitiator(preferred)
+	if err != nil {
+		t.Fatalf("NewInitiator: %v", err)
+	}
+
+	never := asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 2, 2, 20, 6}
+	ntr := NegTokenResp{NegState: NegStateAcceptIncomplete, SupportedMech: MechType(never)}
+	b, err := ntr.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	if _, _, err := neg.Step(b); err == nil {
+		t.Fatal("expected an error when the acceptor selects a mechanism that was never offered, got nil")
+	}
+}
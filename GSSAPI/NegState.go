@@ -0,0 +1,209 @@
+package GSSAPI
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jcmturner/asn1"
+)
+
+// NegState values for NegTokenResp.NegState (RFC 4178 section 4.2.2), named
+// to match the GssState* constants used elsewhere in the ecosystem (e.g.
+// zgrab2/stacktitan's gss package).
+const (
+	NegStateAcceptCompleted  = 0
+	NegStateAcceptIncomplete = 1
+	NegStateReject           = 2
+	NegStateRequestMIC       = 3
+)
+
+// NewInitiator starts a SPNEGO negotiation as the initiator, advertising
+// mechs in preference order, and returns the wire bytes of the first token
+// to send (an InitialContextToken-wrapped NegTokenInit carrying the
+// preferred mechanism's InitialToken). done is always false here; it is
+// returned for symmetry with Step.
+func NewInitiator(mechs ...Mechanism) (neg *Negotiator, token []byte, done bool, err error) {
+	if len(mechs) == 0 {
+		return nil, nil, false, errors.New("GSSAPI: at least one mechanism is required")
+	}
+	mechTypes := make(MechTypeList, len(mechs))
+	for i, m := range mechs {
+		mechTypes[i] = m.OID()
+	}
+	preferred := mechs[0]
+	mechToken, err := preferred.InitialToken()
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("error getting initial token from %s: %v", preferred.OID(), err)
+	}
+	nti := NegTokenInit{
+		MechTypes: mechTypes,
+		MechToken: mechToken,
+	}
+	neg = &Negotiator{
+		role:      roleInitiator,
+		mech:      preferred,
+		mechs:     mechs,
+		mechTypes: mechTypes,
+		negState:  NegStateAcceptIncomplete,
+	}
+	st := SPNEGOToken{Init: nti}
+	token, err = st.Marshal()
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("error marshalling initial SPNEGOToken: %v", err)
+	}
+	return neg, token, false, nil
+}
+
+// Step drives the negotiation forward with a token received from the peer
+// and returns the next token to send, if any, and whether the security
+// context is now established. A nil outgoing token with done true means the
+// negotiation completed and nothing further needs to be sent.
+func (neg *Negotiator) Step(incoming []byte) (outgoing []byte, done bool, err error) {
+	var st SPNEGOToken
+	isInit, tok, err := st.Unmarshal(incoming)
+	if err != nil {
+		return nil, false, fmt.Errorf("error unmarshalling incoming SPNEGO token: %v", err)
+	}
+	if isInit {
+		return neg.stepAccept(tok.(*NegTokenInit))
+	}
+	if neg.role == roleAcceptor {
+		if !neg.repliedOnce {
+			return nil, false, errors.New("GSSAPI: expected a NegTokenInit as the first token")
+		}
+		return neg.stepAcceptContinue(tok.(*NegTokenResp))
+	}
+	return neg.stepInitiate(tok.(*NegTokenResp))
+}
+
+// stepAccept handles a NegTokenInit received by an acceptor, selecting a
+// mechanism via mechSelector (defaulting to SelectMechanism, which NewAcceptor
+// overrides to only agree to its own preference list).
+func (neg *Negotiator) stepAccept(nti *NegTokenInit) (outgoing []byte, done bool, err error) {
+	neg.role = roleAcceptor
+	selector := neg.mechSelector
+	if selector == nil {
+		selector = SelectMechanism
+	}
+	m, _, err := selector(nti.MechTypes)
+	if err != nil {
+		resp := NegTokenResp{NegState: NegStateReject}
+		b, merr := resp.Marshal()
+		if merr != nil {
+			return nil, false, merr
+		}
+		return b, false, err
+	}
+	neg.mech = m
+	neg.mechTypes = nti.MechTypes
+	neg.repliedOnce = true
+	respToken, established, err := m.Step(nti.MechToken)
+	if err != nil {
+		return nil, false, err
+	}
+	resp := NegTokenResp{
+		SupportedMech: MechType(m.OID()),
+		ResponseToken: respToken,
+	}
+	return neg.finishAccept(&resp, established)
+}
+
+// stepAcceptContinue handles a NegTokenResp received by an acceptor after its
+// first reply: either a continuation of the mechanism's own negotiation, or,
+// once RequireMIC has made the acceptor request one, the initiator's
+// mechListMIC. Unlike stepAccept's reply, the NegTokenResp this returns never
+// repeats SupportedMech: RFC 4178 section 4.2.1 only permits that in the
+// first reply from the target.
+func (neg *Negotiator) stepAcceptContinue(ntr *NegTokenResp) (outgoing []byte, done bool, err error) {
+	if neg.awaitingMIC {
+		if len(ntr.MechListMIC) == 0 {
+			return nil, false, errors.New("GSSAPI: expected a mechListMIC from the initiator")
+		}
+		if err := neg.VerifyMechListMIC(ntr.MechListMIC); err != nil {
+			return nil, false, err
+		}
+		neg.awaitingMIC = false
+		neg.done = true
+		neg.negState = NegStateAcceptCompleted
+		resp := NegTokenResp{NegState: NegStateAcceptCompleted}
+		outgoing, err = resp.Marshal()
+		return outgoing, neg.done, err
+	}
+	respToken, established, err := neg.mech.Step(ntr.ResponseToken)
+	if err != nil {
+		return nil, false, err
+	}
+	resp := NegTokenResp{ResponseToken: respToken}
+	return neg.finishAccept(&resp, established)
+}
+
+// finishAccept sets resp.NegState based on whether the mechanism's context is
+// established and, for an acceptor with RequireMIC set, whether a
+// mechListMIC is still owed, then marshals it.
+func (neg *Negotiator) finishAccept(resp *NegTokenResp, established bool) (outgoing []byte, done bool, err error) {
+	switch {
+	case !established:
+		resp.NegState = NegStateAcceptIncomplete
+	case neg.RequireMIC && !neg.awaitingMIC:
+		resp.NegState = NegStateRequestMIC
+		neg.awaitingMIC = true
+	default:
+		resp.NegState = NegStateAcceptCompleted
+		neg.done = true
+	}
+	neg.negState = int(resp.NegState)
+	outgoing, err = resp.Marshal()
+	return outgoing, neg.done, err
+}
+
+// stepInitiate handles a NegTokenResp received by an initiator. On the
+// first reply it honours the acceptor's SupportedMech, falling through to
+// whichever offered mechanism the acceptor actually chose if that was not
+// the initiator's preferred one.
+func (neg *Negotiator) stepInitiate(ntr *NegTokenResp) (outgoing []byte, done bool, err error) {
+	neg.negState = int(ntr.NegState)
+	if !neg.repliedOnce {
+		neg.repliedOnce = true
+		if chosen := asn1.ObjectIdentifier(ntr.SupportedMech); len(chosen) > 0 && !oidEqual(chosen, neg.mech.OID()) {
+			m, ok := neg.mechByOID(chosen)
+			if !ok {
+				return nil, false, fmt.Errorf("GSSAPI: acceptor selected mechanism %s that was never offered", chosen)
+			}
+			neg.mech = m
+		}
+	}
+	switch int(ntr.NegState) {
+	case NegStateReject:
+		return nil, false, errors.New("GSSAPI: peer rejected the SPNEGO negotiation")
+	case NegStateAcceptCompleted:
+		if len(ntr.MechListMIC) > 0 {
+			if err := neg.VerifyMechListMIC(ntr.MechListMIC); err != nil {
+				return nil, false, err
+			}
+		}
+		neg.done = true
+		return nil, true, nil
+	case NegStateRequestMIC:
+		mic, err := neg.SignMechListMIC()
+		if err != nil {
+			return nil, false, err
+		}
+		resp := NegTokenResp{MechListMIC: mic}
+		outgoing, err = resp.Marshal()
+		return outgoing, false, err
+	default: // accept-incomplete
+		respToken, established, err := neg.mech.Step(ntr.ResponseToken)
+		if err != nil {
+			return nil, false, err
+		}
+		resp := NegTokenResp{ResponseToken: respToken}
+		if established {
+			resp.NegState = NegStateAcceptCompleted
+			neg.done = true
+		} else {
+			resp.NegState = NegStateAcceptIncomplete
+		}
+		outgoing, err = resp.Marshal()
+		return outgoing, neg.done, err
+	}
+}
@@ -0,0 +1,119 @@
+package GSSAPI
+
+import (
+	"testing"
+
+	"github.com/jcmturner/asn1"
+)
+
+func TestNegotiatorHandshakeEndToEnd(t *testing.T) {
+	initMech := &fakeMechanism{oid: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 2, 2, 22, 1}, stepsToEstablish: 2}
+	acceptMech := &fakeMechanism{oid: initMech.oid, stepsToEstablish: 2}
+	RegisterMechanism(acceptMech)
+
+	initiator, token, done, err := NewInitiator(initMech)
+	if err != nil {
+		t.Fatalf("NewInitiator: %v", err)
+	}
+	if done {
+		t.Fatal("NewInitiator reported done before any exchange")
+	}
+
+	acceptor, err := NewAcceptor(acceptMech)
+	if err != nil {
+		t.Fatalf("NewAcceptor: %v", err)
+	}
+
+	token, acceptorDone, err := acceptor.Accept(token)
+	if err != nil {
+		t.Fatalf("Accept: %v", err)
+	}
+	if acceptorDone {
+		t.Fatal("acceptor reported done after its first reply, expected a second round")
+	}
+
+	var initiatorDone bool
+	for round := 0; !initiatorDone && !acceptorDone; round++ {
+		if round > 5 {
+			t.Fatal("handshake did not converge")
+		}
+		token, initiatorDone, err = initiator.Step(token)
+		if err != nil {
+			t.Fatalf("initiator.Step: %v", err)
+		}
+		if initiatorDone {
+			break
+		}
+		token, acceptorDone, err = acceptor.Step(token)
+		if err != nil {
+			t.Fatalf("acceptor.Step: %v", err)
+		}
+	}
+	if !initiatorDone && !acceptorDone {
+		t.Fatal("handshake never established the security context")
+	}
+}
+
+func TestNegotiatorHandshakeWithRequireMIC(t *testing.T) {
+	initMech := &fakeMechanism{oid: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 2, 2, 22, 2}}
+	acceptMech := &fakeMechanism{oid: initMech.oid}
+	RegisterMechanism(acceptMech)
+
+	initiator, token, _, err := NewInitiator(initMech)
+	if err != nil {
+		t.Fatalf("NewInitiator: %v", err)
+	}
+
+	acceptor, err := NewAcceptor(acceptMech)
+	if err != nil {
+		t.Fatalf("NewAcceptor: %v", err)
+	}
+	acceptor.RequireMIC = true
+
+	token, acceptorDone, err := acceptor.Accept(token)
+	if err != nil {
+		t.Fatalf("Accept: %v", err)
+	}
+	if acceptorDone {
+		t.Fatal("acceptor should still be waiting for a mechListMIC")
+	}
+
+	token, initiatorDone, err := initiator.Step(token)
+	if err != nil {
+		t.Fatalf("initiator.Step (request-mic): %v", err)
+	}
+	if initiatorDone {
+		t.Fatal("initiator should not be done until it has sent its mechListMIC")
+	}
+
+	_, acceptorDone, err = acceptor.Step(token)
+	if err != nil {
+		t.Fatalf("acceptor.Step (mechListMIC): %v", err)
+	}
+	if !acceptorDone {
+		t.Error("expected the acceptor to be done once the mechListMIC verified")
+	}
+}
+
+func TestNegotiatorRejectsUnknownMechanism(t *testing.T) {
+	acceptor, err := NewAcceptor(&fakeMechanism{oid: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 2, 2, 22, 3}})
+	if err != nil {
+		t.Fatalf("NewAcceptor: %v", err)
+	}
+
+	unsupported := &fakeMechanism{oid: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 2, 2, 22, 4}}
+	nti := NegTokenInit{MechTypes: MechTypeList{unsupported.OID()}, MechToken: []byte("hello")}
+	st := SPNEGOToken{Init: nti}
+	b, err := st.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	_, done, err := acceptor.Accept(b)
+	if err == nil {
+		t.Fatal("expected an error for a mechanism the acceptor does not support")
+	}
+	if done {
+		t.Error("expected done to be false on rejection")
+	}
+}
@@ -64,7 +64,11 @@ type NegTokenResp struct {
 // The boolean indicates if the reponse is a NegTokenInit.
 // If error is nil and the boolean is false the response is a NegTokenResp.
 func (n *NegotiationToken) Unmarshal(b []byte) (bool, interface{}, error) {
-	_, err := asn1.Unmarshal(b, n)
+	// asn1.Marshal/Unmarshal only special-case the literal asn1.RawValue
+	// type; NegotiationToken is a distinct named type with the same
+	// underlying fields, so it must be cast back to asn1.RawValue here to
+	// get the raw CHOICE tag/bytes instead of being decoded as a SEQUENCE.
+	_, err := asn1.Unmarshal(b, (*asn1.RawValue)(n))
 	if err != nil {
 		return false, nil, fmt.Errorf("Error unmarshalling NegotiationToken: %v", err)
 	}
@@ -81,7 +85,7 @@ func (n *NegotiationToken) Unmarshal(b []byte) (bool, interface{}, error) {
 	}
 	_, err = asn1.Unmarshal(n.Bytes, negToken)
 	if err != nil {
-		return nil, fmt.Errorf("Error unmarshalling NegotiationToken type %d: %v", n.Tag, err)
+		return false, nil, fmt.Errorf("Error unmarshalling NegotiationToken type %d: %v", n.Tag, err)
 	}
 	return isInit, negToken, nil
 }
@@ -92,7 +96,7 @@ func (n *NegTokenInit) Marshal() ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	nt := NegotiationToken{
+	nt := asn1.RawValue{
 		Tag:        0,
 		Class:      2,
 		IsCompound: true,
@@ -111,7 +115,7 @@ func (n *NegTokenResp) Marshal() ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	nt := NegotiationToken{
+	nt := asn1.RawValue{
 		Tag:        1,
 		Class:      2,
 		IsCompound: true,
@@ -0,0 +1,90 @@
+package GSSAPI
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jcmturner/asn1"
+)
+
+// negotiatorRole records which side of the exchange a Negotiator is
+// driving, since a NegTokenResp is handled differently by an initiator
+// (continuing its own mechanism) than by an acceptor (continuing to accept
+// the client's mechanism, or waiting on a requested mechListMIC).
+type negotiatorRole int
+
+const (
+	roleInitiator negotiatorRole = iota
+	roleAcceptor
+)
+
+// Negotiator drives a SPNEGO negotiation exchange on behalf of either an
+// initiator or an acceptor. It is built on top of NegTokenInit/NegTokenResp
+// and keeps the state that spans multiple tokens on the wire: the
+// MechTypeList the mechListMIC is computed over, and the mechanism chosen
+// for the exchange. Acceptor embeds a Negotiator configured with mechSelector
+// so that the accept-side state machine lives here once and is shared.
+type Negotiator struct {
+	role      negotiatorRole
+	mech      Mechanism    // the mechanism selected for this exchange
+	mechs     []Mechanism  // mechanisms offered by an initiator, most preferred first
+	mechTypes MechTypeList // MechTypeList the mechListMIC is computed over, as originally sent
+
+	// mechSelector picks a Mechanism out of a client's offered MechTypeList
+	// on the acceptor side. NewAcceptor sets this to intersect with a
+	// server's own preference list; it defaults to SelectMechanism.
+	mechSelector func(MechTypeList) (Mechanism, int, error)
+
+	negState    int  // last NegState seen/sent
+	done        bool // true once the security context is established
+	repliedOnce bool // true once the first reply has been produced/consumed
+	awaitingMIC bool // acceptor only: true once request-mic has been sent and we are waiting on the mechListMIC
+	RequireMIC  bool // acceptor only: demand a mechListMIC before accept-completed
+}
+
+// mechByOID returns the mechanism amongst those offered that matches oid.
+func (neg *Negotiator) mechByOID(oid asn1.ObjectIdentifier) (Mechanism, bool) {
+	for _, m := range neg.mechs {
+		if oidEqual(m.OID(), oid) {
+			return m, true
+		}
+	}
+	return nil, false
+}
+
+// ComputeMechListMIC computes the mechListMIC for a NegTokenResp: the
+// mechanism's GetMIC over the DER encoding of the MechTypeList from the
+// initial NegTokenInit (RFC 4178 section 5).
+func ComputeMechListMIC(ctx Mechanism, mechList MechTypeList) ([]byte, error) {
+	b, err := asn1.Marshal(mechList)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling MechTypeList for mechListMIC: %v", err)
+	}
+	return ctx.GetMIC(b)
+}
+
+// SignMechListMIC computes the mechListMIC over the MechTypeList this
+// Negotiator originally sent, signed by the mechanism it has selected. The
+// result is intended for the NegTokenResp.MechListMIC field.
+func (neg *Negotiator) SignMechListMIC() ([]byte, error) {
+	if neg.mech == nil {
+		return nil, errors.New("GSSAPI: no mechanism selected to sign mechListMIC")
+	}
+	return ComputeMechListMIC(neg.mech, neg.mechTypes)
+}
+
+// VerifyMechListMIC verifies a mechListMIC received from the peer against
+// the MechTypeList of the initial NegTokenInit: the one this Negotiator sent
+// as an initiator, or received as an acceptor. Step calls this when an
+// acceptor that sent request-mic receives the initiator's reply, and when an
+// initiator receives an accept-completed NegTokenResp carrying a MechListMIC.
+func (neg *Negotiator) VerifyMechListMIC(mic []byte) error {
+	if neg.mech == nil {
+		return errors.New("GSSAPI: no mechanism selected to verify mechListMIC")
+	}
+	b, err := asn1.Marshal(neg.mechTypes)
+	if err != nil {
+		return fmt.Errorf("error marshalling MechTypeList for mechListMIC: %v", err)
+	}
+	return neg.mech.VerifyMIC(b, mic)
+}
@@ -0,0 +1,57 @@
+package GSSAPI
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jcmturner/asn1"
+)
+
+func TestComputeMechListMIC(t *testing.T) {
+	m := &fakeMechanism{oid: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 2, 2, 21, 1}}
+	mechTypes := MechTypeList{m.OID(), KerberosOID}
+
+	mic, err := ComputeMechListMIC(m, mechTypes)
+	if err != nil {
+		t.Fatalf("ComputeMechListMIC: %v", err)
+	}
+
+	want, err := asn1.Marshal(mechTypes)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	wantMIC, err := m.GetMIC(want)
+	if err != nil {
+		t.Fatalf("GetMIC: %v", err)
+	}
+	if !bytes.Equal(mic, wantMIC) {
+		t.Errorf("ComputeMechListMIC = % x, want % x", mic, wantMIC)
+	}
+}
+
+func TestSignAndVerifyMechListMIC(t *testing.T) {
+	m := &fakeMechanism{oid: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 2, 2, 21, 2}}
+	neg := &Negotiator{mech: m, mechTypes: MechTypeList{m.OID()}}
+
+	mic, err := neg.SignMechListMIC()
+	if err != nil {
+		t.Fatalf("SignMechListMIC: %v", err)
+	}
+	if err := neg.VerifyMechListMIC(mic); err != nil {
+		t.Errorf("VerifyMechListMIC rejected a MIC it just signed: %v", err)
+	}
+}
+
+func TestVerifyMechListMICRejectsTamperedMIC(t *testing.T) {
+	m := &fakeMechanism{oid: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 2, 2, 21, 3}}
+	neg := &Negotiator{mech: m, mechTypes: MechTypeList{m.OID()}}
+
+	mic, err := neg.SignMechListMIC()
+	if err != nil {
+		t.Fatalf("SignMechListMIC: %v", err)
+	}
+	mic[len(mic)-1] ^= 0xFF
+	if err := neg.VerifyMechListMIC(mic); err == nil {
+		t.Error("expected VerifyMechListMIC to reject a tampered MIC, got nil")
+	}
+}
@@ -0,0 +1,123 @@
+package GSSAPI
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jcmturner/asn1"
+)
+
+// SPNEGOOID is the object identifier of the SPNEGO mechanism (RFC 4178 section 1).
+var SPNEGOOID = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 2}
+
+/*
+RFC 2743 section 3.1 defines the outer wrapping that a GSS-API mechanism
+must use for the very first token it emits on the wire:
+
+InitialContextToken ::=
+-- option indication (delegation, etc.) indicated within
+-- mechanism-specific token
+[APPLICATION 0] IMPLICIT SEQUENCE {
+        thisMech        MechType,
+        innerContextToken ANY DEFINED BY thisMech
+           -- contents mechanism-specific
+}
+
+For SPNEGO, thisMech is always the SPNEGO OID and innerContextToken is the
+[0] NegTokenInit NegotiationToken CHOICE value. Windows SMB/HTTP-Negotiate
+servers will not accept a bare NegTokenInit as the first token; they expect
+this wrapping.
+*/
+
+// initialContextToken is the SEQUENCE content of an InitialContextToken.
+type initialContextToken struct {
+	ThisMech          asn1.ObjectIdentifier
+	InnerContextToken asn1.RawValue
+}
+
+// SPNEGOToken wraps a NegTokenInit in the RFC 2743 InitialContextToken form
+// that SPNEGO initiators must send as the first token on the wire.
+type SPNEGOToken struct {
+	Init NegTokenInit
+}
+
+// Marshal returns the InitialContextToken wrapped bytes of the NegTokenInit:
+// an [APPLICATION 0] IMPLICIT SEQUENCE carrying the SPNEGO OID ahead of the
+// [0] NegTokenInit CHOICE value.
+func (s *SPNEGOToken) Marshal() ([]byte, error) {
+	inner, err := s.Init.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling NegTokenInit for SPNEGOToken: %v", err)
+	}
+	ct := initialContextToken{
+		ThisMech:          SPNEGOOID,
+		InnerContextToken: asn1.RawValue{FullBytes: inner},
+	}
+	b, err := asn1.Marshal(ct)
+	if err != nil {
+		return nil, err
+	}
+	// Reinterpret the universal SEQUENCE encoding above as the content of an
+	// [APPLICATION 0] IMPLICIT SEQUENCE by retagging it.
+	var seq asn1.RawValue
+	if _, err := asn1.Unmarshal(b, &seq); err != nil {
+		return nil, err
+	}
+	ict := asn1.RawValue{
+		Class:      1, // application
+		Tag:        0,
+		IsCompound: true,
+		Bytes:      seq.Bytes,
+	}
+	return asn1.Marshal(ict)
+}
+
+// Unmarshal detects the InitialContextToken [APPLICATION 0] wrapping, if
+// present, verifies the mechanism OID is SPNEGO and strips it, then
+// dispatches the remaining bytes to NegotiationToken.Unmarshal. Bytes that
+// are not wrapped are passed straight through, since only the very first
+// token on the wire carries the wrapper.
+func (s *SPNEGOToken) Unmarshal(b []byte) (bool, interface{}, error) {
+	var rv asn1.RawValue
+	if _, err := asn1.Unmarshal(b, &rv); err != nil {
+		return false, nil, fmt.Errorf("error unmarshalling SPNEGOToken: %v", err)
+	}
+	if rv.Class != 1 || rv.Tag != 0 {
+		var nt NegotiationToken
+		return nt.Unmarshal(b)
+	}
+	// Retag the IMPLICIT SEQUENCE content back to a universal SEQUENCE so it
+	// can be unmarshalled into initialContextToken.
+	useq := asn1.RawValue{
+		Class:      0, // universal
+		Tag:        16,
+		IsCompound: true,
+		Bytes:      rv.Bytes,
+	}
+	ub, err := asn1.Marshal(useq)
+	if err != nil {
+		return false, nil, err
+	}
+	var ct initialContextToken
+	if _, err := asn1.Unmarshal(ub, &ct); err != nil {
+		return false, nil, fmt.Errorf("error unmarshalling InitialContextToken: %v", err)
+	}
+	if !oidEqual(ct.ThisMech, SPNEGOOID) {
+		return false, nil, errors.New("InitialContextToken thisMech is not the SPNEGO OID")
+	}
+	var nt NegotiationToken
+	return nt.Unmarshal(ct.InnerContextToken.FullBytes)
+}
+
+// oidEqual reports whether two object identifiers are equal.
+func oidEqual(a, b asn1.ObjectIdentifier) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,97 @@
+package GSSAPI
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// knownGoodInitialContextTokenHex is the wire form of a NegTokenInit
+// advertising only the Kerberos OID with a 7-byte mechToken: an
+// [APPLICATION 0] tag (0x60), the SPNEGO OID (06 06 2b0601050502), then the
+// [0] NegTokenInit CHOICE value. Fixed here byte-for-byte so a regression in
+// the ASN.1 tagging is caught even if the round-trip test below is not.
+const knownGoodInitialContextTokenHex = "" +
+	"602606062b0601050502a01c301aa00d300b06092a864886f712010202a2090407" +
+	"41414141414141"
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("bad hex fixture: %v", err)
+	}
+	return b
+}
+
+func TestSPNEGOTokenMarshalUnmarshalRoundTrip(t *testing.T) {
+	nti := NegTokenInit{
+		MechTypes: MechTypeList{KerberosOID},
+		MechToken: []byte{0xAA},
+	}
+	st := SPNEGOToken{Init: nti}
+	b, err := st.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal returned an error: %v", err)
+	}
+	if len(b) == 0 || b[0] != 0x60 {
+		t.Fatalf("expected an [APPLICATION 0] constructed tag (0x60) as the first byte, got % x", b)
+	}
+
+	var got SPNEGOToken
+	isInit, tok, err := got.Unmarshal(b)
+	if err != nil {
+		t.Fatalf("Unmarshal returned an error: %v", err)
+	}
+	if !isInit {
+		t.Fatal("expected isInit to be true for a wrapped NegTokenInit")
+	}
+	gotNTI, ok := tok.(*NegTokenInit)
+	if !ok {
+		t.Fatalf("expected *NegTokenInit, got %T", tok)
+	}
+	if !bytes.Equal(gotNTI.MechToken, nti.MechToken) {
+		t.Errorf("MechToken round-trip mismatch: got % x, want % x", gotNTI.MechToken, nti.MechToken)
+	}
+	if len(gotNTI.MechTypes) != 1 || !oidEqual(gotNTI.MechTypes[0], KerberosOID) {
+		t.Errorf("MechTypes round-trip mismatch: got %v, want %v", gotNTI.MechTypes, nti.MechTypes)
+	}
+}
+
+func TestSPNEGOTokenMarshalMatchesKnownGoodBytes(t *testing.T) {
+	nti := NegTokenInit{
+		MechTypes: MechTypeList{KerberosOID},
+		MechToken: []byte("AAAAAAA"),
+	}
+	st := SPNEGOToken{Init: nti}
+	b, err := st.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal returned an error: %v", err)
+	}
+	want := mustDecodeHex(t, knownGoodInitialContextTokenHex)
+	if !bytes.Equal(b, want) {
+		t.Errorf("Marshal produced unexpected bytes:\ngot:  % x\nwant: % x", b, want)
+	}
+}
+
+func TestSPNEGOTokenUnmarshalKnownGoodBytes(t *testing.T) {
+	b := mustDecodeHex(t, knownGoodInitialContextTokenHex)
+	var st SPNEGOToken
+	isInit, tok, err := st.Unmarshal(b)
+	if err != nil {
+		t.Fatalf("Unmarshal returned an error on known-good bytes: %v", err)
+	}
+	if !isInit {
+		t.Fatal("expected isInit to be true")
+	}
+	nti, ok := tok.(*NegTokenInit)
+	if !ok {
+		t.Fatalf("expected *NegTokenInit, got %T", tok)
+	}
+	if len(nti.MechTypes) != 1 || !oidEqual(nti.MechTypes[0], KerberosOID) {
+		t.Errorf("expected the Kerberos OID as the sole offered mechanism, got %v", nti.MechTypes)
+	}
+	if string(nti.MechToken) != "AAAAAAA" {
+		t.Errorf("MechToken mismatch: got %q, want %q", nti.MechToken, "AAAAAAA")
+	}
+}